@@ -1,7 +1,13 @@
 package chans
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Deref safely dereferences a pointer and returns the value or an error
@@ -97,6 +103,684 @@ func Close[T any, C ~chan T](ch C) error {
 	return err
 }
 
+// TrySend attempts a non-blocking send to a channel. It returns sent == true
+// if the value was delivered, sent == false if the channel was not ready to
+// receive, and an error for nil or closed channels, or if sending causes a
+// panic.
+func TrySend[T any, C ~chan T](ch C, value T) (sent bool, err error) {
+	if ch == nil {
+		return false, fmt.Errorf("[send on nil channel] channel(%v) value(%v)", ch, value)
+	}
+	(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("[channel send panic] %v channel(%v) value(%v)", r, ch, value)
+			}
+		}()
+		c := (chan T)(ch)
+		if c == nil {
+			err = fmt.Errorf("[send on nil channel] channel(%v) value(%v)", ch, value)
+			return
+		}
+		select {
+		case c <- value:
+			sent = true
+		default:
+		}
+	})()
+	return sent, err
+}
+
+// TryReceive attempts a non-blocking receive from a channel. It returns
+// received == true if a value was read, received == false if no value was
+// ready, and an error for nil or closed channels, or if receiving causes a
+// panic.
+func TryReceive[T any, C ~chan T](ch C) (value T, received bool, err error) {
+	if ch == nil {
+		return value, false, fmt.Errorf("[receive on nil channel] channel(%v) result(%v)", ch, value)
+	}
+	(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("[channel receive panic] %v channel(%v) result(%v)", r, ch, value)
+			}
+		}()
+		c := (chan T)(ch)
+		if c == nil {
+			err = fmt.Errorf("[receive on nil channel] channel(%v) result(%v)", ch, value)
+			return
+		}
+		var ok bool
+		select {
+		case value, ok = <-c:
+			if !ok {
+				err = fmt.Errorf("[receive on closed channel] channel(%v) result(%v)", ch, value)
+				return
+			}
+			received = true
+		default:
+		}
+	})()
+	return value, received, err
+}
+
+// SendCtx safely sends a value to a channel, returning ctx.Err() if ctx is
+// cancelled or its deadline expires before the send completes, and the same
+// nil-channel / panic-wrapped errors as Send otherwise.
+func SendCtx[T any, C ~chan T](ctx context.Context, ch C, value T) error {
+	if ch == nil {
+		return fmt.Errorf("[send on nil channel] channel(%v) value(%v)", ch, value)
+	}
+	var err error
+	(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("[channel send panic] %v channel(%v) value(%v)", r, ch, value)
+			}
+		}()
+		c := (chan T)(ch)
+		if c == nil {
+			err = fmt.Errorf("[send on nil channel] channel(%v) value(%v)", ch, value)
+			return
+		}
+		select {
+		case c <- value:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	})()
+	return err
+}
+
+// ReceiveCtx safely receives a value from a channel, returning ctx.Err() if ctx
+// is cancelled or its deadline expires before a value arrives, and the same
+// nil-channel / closed-channel / panic-wrapped errors as Receive otherwise.
+func ReceiveCtx[T any, C ~chan T](ctx context.Context, ch C) (T, error) {
+	var result T
+	if ch == nil {
+		return result, fmt.Errorf("[receive on nil channel] channel(%v) result(%v)", ch, result)
+	}
+	var err error
+	(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("[channel receive panic] %v channel(%v) result(%v)", r, ch, result)
+			}
+		}()
+		c := (chan T)(ch)
+		if c == nil {
+			err = fmt.Errorf("[receive on nil channel] channel(%v) result(%v)", ch, result)
+			return
+		}
+		var ok bool
+		select {
+		case result, ok = <-c:
+			if !ok {
+				err = fmt.Errorf("[receive on closed channel] channel(%v) result(%v)", ch, result)
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	})()
+	return result, err
+}
+
+// SendTimeout is a convenience wrapper around SendCtx that builds a
+// context.WithTimeout from the given duration.
+func SendTimeout[T any, C ~chan T](ch C, value T, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return SendCtx(ctx, ch, value)
+}
+
+// ReceiveTimeout is a convenience wrapper around ReceiveCtx that builds a
+// context.WithTimeout from the given duration.
+func ReceiveTimeout[T any, C ~chan T](ch C, timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return ReceiveCtx(ctx, ch)
+}
+
+// ErrPipeClosed is returned by a PipeEnd's Send or Receive once either end
+// of the pipe has been closed.
+var ErrPipeClosed = fmt.Errorf("[pipe closed]")
+
+// PipeEnd is one side of an in-process, full-duplex pipe created by Pipe.
+// Send delivers a value to the peer end and Receive reads a value the peer
+// sent, both giving way to the pipe's close signal so neither side can
+// block forever on a dead peer.
+type PipeEnd[T any] struct {
+	w       chan T
+	r       chan T
+	closing chan struct{}
+	closed  *atomic.Int32
+}
+
+// Pipe creates a pair of connected PipeEnd values, each reading what the
+// other writes, backed by unbuffered channels and a shared close signal.
+func Pipe[T any]() (*PipeEnd[T], *PipeEnd[T]) {
+	c1 := make(chan T)
+	c2 := make(chan T)
+	closing := make(chan struct{})
+	closed := &atomic.Int32{}
+	end1 := &PipeEnd[T]{w: c1, r: c2, closing: closing, closed: closed}
+	end2 := &PipeEnd[T]{w: c2, r: c1, closing: closing, closed: closed}
+	return end1, end2
+}
+
+// Send delivers value to the peer end, returning ErrPipeClosed if the pipe
+// has been closed before or while the send is in flight.
+func (p *PipeEnd[T]) Send(value T) error {
+	if p.closed.Load() != 0 {
+		return ErrPipeClosed
+	}
+	select {
+	case p.w <- value:
+		return nil
+	case <-p.closing:
+		return ErrPipeClosed
+	}
+}
+
+// Receive reads a value sent by the peer end, returning ErrPipeClosed if
+// the pipe has been closed before or while the receive is in flight.
+func (p *PipeEnd[T]) Receive() (T, error) {
+	var result T
+	if p.closed.Load() != 0 {
+		return result, ErrPipeClosed
+	}
+	select {
+	case result = <-p.r:
+		return result, nil
+	case <-p.closing:
+		return result, ErrPipeClosed
+	}
+}
+
+// Close shuts down the pipe for both ends. It is idempotent: only the first
+// call on either end actually closes the shared closing channel.
+func (p *PipeEnd[T]) Close() error {
+	if p.closed.CompareAndSwap(0, 1) {
+		close(p.closing)
+	}
+	return nil
+}
+
+// closeOnces tracks a sync.Once per channel, keyed by the channel value
+// itself (channels are comparable and hash fine as an interface{} key), so
+// that repeated calls to CloseOnce for the same channel are no-ops instead
+// of panicking. Keying by the channel value rather than its pointer also
+// keeps the channel reachable for as long as its entry exists, so a
+// different channel can never be mistaken for it after the original is
+// garbage collected and its address reused. Entries are never evicted, so
+// long-running processes that call CloseOnce on many short-lived channels
+// should expect closeOnces to grow unbounded; that tradeoff is deliberate
+// in exchange for correctness.
+var closeOnces sync.Map // map[any]*sync.Once
+
+// CloseOnce closes a channel exactly once, no matter how many times it is
+// called for the same channel. Later calls return nil instead of panicking
+// on an already-closed channel.
+func CloseOnce[T any, C ~chan T](ch C) error {
+	if ch == nil {
+		return fmt.Errorf("[close on nil channel] channel(%v)", ch)
+	}
+	actual, _ := closeOnces.LoadOrStore(ch, &sync.Once{})
+	once := actual.(*sync.Once)
+	var err error
+	once.Do(func() {
+		err = Close(ch)
+	})
+	return err
+}
+
+// SafeChan wraps a channel with a sync.Once, an atomic closed flag, and a
+// mutex so that Send, Receive, TryReceive, and Close are all safe against
+// double-close and send-after-close without needing to recover from a
+// panic: Close takes the mutex's write lock, so it can never run
+// concurrently with an in-flight Send/Receive/TryReceive (each of which
+// holds the read lock), and the closed channel is never operated on after
+// Close has run.
+type SafeChan[T any] struct {
+	ch     chan T
+	mu     sync.RWMutex
+	once   *sync.Once
+	closed *atomic.Bool
+}
+
+// NewSafeChan creates a SafeChan wrapping a newly allocated channel with
+// the given buffer size.
+func NewSafeChan[T any](buffer int) *SafeChan[T] {
+	return &SafeChan[T]{
+		ch:     make(chan T, buffer),
+		once:   &sync.Once{},
+		closed: &atomic.Bool{},
+	}
+}
+
+// Send sends a value on the wrapped channel, returning an error instead of
+// panicking if the channel has already been closed.
+func (s *SafeChan[T]) Send(value T) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed.Load() {
+		return fmt.Errorf("[send on closed channel] channel(%v) value(%v)", s.ch, value)
+	}
+	s.ch <- value
+	return nil
+}
+
+// Receive receives a value from the wrapped channel, reporting via the
+// returned error whether the channel was closed.
+func (s *SafeChan[T]) Receive() (T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var zero T
+	if s.closed.Load() {
+		return zero, fmt.Errorf("[receive on closed channel] channel(%v) result(%v)", s.ch, zero)
+	}
+	value, ok := <-s.ch
+	if !ok {
+		return value, fmt.Errorf("[receive on closed channel] channel(%v) result(%v)", s.ch, value)
+	}
+	return value, nil
+}
+
+// TryReceive performs a non-blocking receive from the wrapped channel.
+func (s *SafeChan[T]) TryReceive() (T, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var value T
+	if s.closed.Load() {
+		return value, false, fmt.Errorf("[receive on closed channel] channel(%v) result(%v)", s.ch, value)
+	}
+	select {
+	case value = <-s.ch:
+		return value, true, nil
+	default:
+		return value, false, nil
+	}
+}
+
+// Close closes the wrapped channel exactly once; subsequent calls are
+// no-ops that return nil.
+func (s *SafeChan[T]) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.once.Do(func() {
+		s.closed.Store(true)
+		close(s.ch)
+	})
+	return nil
+}
+
+// Subscription represents an active subscription created by a Transport's
+// Subscribe method. Unsubscribe stops delivery of further messages.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Transport is the pluggable pub/sub backend that BindSendChan and
+// BindReceiveChan publish to and subscribe through, modeled on the NATS
+// EncodedConn pattern so any messaging system can back a bound channel.
+type Transport interface {
+	Publish(subject string, data []byte) error
+	Subscribe(subject string, handler func([]byte)) (Subscription, error)
+}
+
+// Codec encodes and decodes values of type T for transport over a
+// Transport's byte-oriented Publish/Subscribe.
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec is the default Codec, encoding and decoding values with
+// encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode marshals value to JSON.
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode unmarshals data into a value of type T.
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// BindSendChan spawns a goroutine that safely drains ch, encodes each value
+// with codec, and publishes it on subject until ch is closed, at which
+// point the goroutine exits. A panicking or nil codec does not crash the
+// goroutine: the encode error is dropped unless an onErr callback is given,
+// in which case it is reported there instead.
+func BindSendChan[T any](t Transport, subject string, ch <-chan T, codec Codec[T], onErr ...func(error)) error {
+	if t == nil {
+		return fmt.Errorf("[bind send on nil transport] subject(%v)", subject)
+	}
+	if ch == nil {
+		return fmt.Errorf("[bind send on nil channel] subject(%v)", subject)
+	}
+	var reportErr func(error)
+	if len(onErr) > 0 {
+		reportErr = onErr[0]
+	}
+	go func() {
+		for value := range ch {
+			data, err := safeEncode(codec, value)
+			if err != nil {
+				if reportErr != nil {
+					reportErr(err)
+				}
+				continue
+			}
+			_ = t.Publish(subject, data)
+		}
+	}()
+	return nil
+}
+
+// BindReceiveChan subscribes to subject on t and, for each message received,
+// decodes it with codec and safely forwards it onto ch via Send. Decode
+// errors (including a panicking or nil codec) and send failures (for
+// example because ch has been closed) are reported through the optional
+// onErr callback rather than propagated, since they happen asynchronously
+// inside the subscription handler.
+func BindReceiveChan[T any](t Transport, subject string, ch chan<- T, codec Codec[T], onErr ...func(error)) (Subscription, error) {
+	if t == nil {
+		return nil, fmt.Errorf("[bind receive on nil transport] subject(%v)", subject)
+	}
+	if ch == nil {
+		return nil, fmt.Errorf("[bind receive on nil channel] subject(%v)", subject)
+	}
+	var reportErr func(error)
+	if len(onErr) > 0 {
+		reportErr = onErr[0]
+	}
+	return t.Subscribe(subject, func(data []byte) {
+		value, err := safeDecode(codec, data)
+		if err != nil {
+			if reportErr != nil {
+				reportErr(err)
+			}
+			return
+		}
+		if sendErr := safeSend(ch, value); sendErr != nil && reportErr != nil {
+			reportErr(sendErr)
+		}
+	})
+}
+
+// safeEncode calls codec.Encode, converting a panic (for example from a
+// nil codec) into an error instead of letting it escape the caller's
+// goroutine.
+func safeEncode[T any](codec Codec[T], value T) (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[codec encode panic] %v value(%v)", r, value)
+		}
+	}()
+	return codec.Encode(value)
+}
+
+// safeDecode calls codec.Decode, converting a panic (for example from a
+// nil codec) into an error instead of letting it escape the caller's
+// goroutine.
+func safeDecode[T any](codec Codec[T], data []byte) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[codec decode panic] %v", r)
+		}
+	}()
+	return codec.Decode(data)
+}
+
+// safeSend sends value on a send-only channel, recovering from a panic on
+// an already-closed channel the same way Send does for bidirectional ones.
+func safeSend[T any](ch chan<- T, value T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[channel send panic] %v channel(%v) value(%v)", r, ch, value)
+		}
+	}()
+	ch <- value
+	return nil
+}
+
+// memSubscription is the Subscription returned by MemTransport.Subscribe.
+type memSubscription struct {
+	transport *MemTransport
+	subject   string
+	id        int
+}
+
+// Unsubscribe removes the associated handler from the MemTransport.
+func (s *memSubscription) Unsubscribe() error {
+	s.transport.mu.Lock()
+	defer s.transport.mu.Unlock()
+	delete(s.transport.handlers[s.subject], s.id)
+	return nil
+}
+
+// MemTransport is an in-memory Transport, dispatching each Publish call
+// synchronously to every handler registered for the subject. It is
+// intended for tests and local development, not for production use across
+// process boundaries.
+type MemTransport struct {
+	mu       sync.Mutex
+	handlers map[string]map[int]func([]byte)
+	nextID   int
+}
+
+// NewMemTransport creates an empty in-memory Transport.
+func NewMemTransport() *MemTransport {
+	return &MemTransport{handlers: make(map[string]map[int]func([]byte))}
+}
+
+// Publish invokes every handler currently subscribed to subject with data.
+func (m *MemTransport) Publish(subject string, data []byte) error {
+	m.mu.Lock()
+	handlers := make([]func([]byte), 0, len(m.handlers[subject]))
+	for _, h := range m.handlers[subject] {
+		handlers = append(handlers, h)
+	}
+	m.mu.Unlock()
+	for _, h := range handlers {
+		h(data)
+	}
+	return nil
+}
+
+// Subscribe registers handler to be called for every subsequent Publish on
+// subject, returning a Subscription that removes it.
+func (m *MemTransport) Subscribe(subject string, handler func([]byte)) (Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.handlers[subject] == nil {
+		m.handlers[subject] = make(map[int]func([]byte))
+	}
+	id := m.nextID
+	m.nextID++
+	m.handlers[subject][id] = handler
+	return &memSubscription{transport: m, subject: subject, id: id}, nil
+}
+
+// ReceiveCase is one branch of a SelectReceive call: either a channel to receive
+// from, or (when Default is true) the branch taken if no other case is
+// ready.
+type ReceiveCase[T any] struct {
+	Chan    chan T
+	Default bool
+}
+
+// SendCase is one branch of a SelectSend call: either a channel and value
+// to send, or (when Default is true) the branch taken if no other case is
+// ready.
+type SendCase[T any] struct {
+	Chan    chan T
+	Value   T
+	Default bool
+}
+
+// SelectReceive waits on all the given cases the way a raw select statement
+// would, returning the index of the case that fired and the value it
+// received. index is -1 if a Default case fired. Any panic raised by a
+// malformed case (for example more than one Default) is converted into an
+// error instead of propagating.
+func SelectReceive[T any](cases ...ReceiveCase[T]) (index int, value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[select receive panic] %v", r)
+		}
+	}()
+	scases := make([]reflect.SelectCase, 0, len(cases))
+	origIndex := make([]int, 0, len(cases))
+	defaultIdx := -1
+	for i, c := range cases {
+		if c.Default {
+			if defaultIdx != -1 {
+				return -1, value, fmt.Errorf("[select has more than one default case]")
+			}
+			defaultIdx = i
+			continue
+		}
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.Chan)})
+		origIndex = append(origIndex, i)
+	}
+	if defaultIdx != -1 {
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+	chosen, recv, recvOK := reflect.Select(scases)
+	if defaultIdx != -1 && chosen == len(scases)-1 {
+		return -1, value, nil
+	}
+	index = origIndex[chosen]
+	if !recvOK {
+		return index, value, fmt.Errorf("[receive on closed channel] channel(%v) result(%v)", cases[index].Chan, value)
+	}
+	value, _ = recv.Interface().(T)
+	return index, value, nil
+}
+
+// SelectSend waits on all the given cases the way a raw select statement
+// would, sending the case's Value once its Chan is ready, and returns the
+// index of the case that fired. index is -1 if a Default case fired. Any
+// panic raised by a malformed case (for example more than one Default, or
+// sending on a closed channel) is converted into an error instead of
+// propagating.
+func SelectSend[T any](cases ...SendCase[T]) (index int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[select send panic] %v", r)
+		}
+	}()
+	scases := make([]reflect.SelectCase, 0, len(cases))
+	origIndex := make([]int, 0, len(cases))
+	defaultIdx := -1
+	for i, c := range cases {
+		if c.Default {
+			if defaultIdx != -1 {
+				return -1, fmt.Errorf("[select has more than one default case]")
+			}
+			defaultIdx = i
+			continue
+		}
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(c.Chan), Send: reflect.ValueOf(c.Value)})
+		origIndex = append(origIndex, i)
+	}
+	if defaultIdx != -1 {
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+	chosen, _, _ := reflect.Select(scases)
+	if defaultIdx != -1 && chosen == len(scases)-1 {
+		return -1, nil
+	}
+	return origIndex[chosen], nil
+}
+
+// SelectSendCtx behaves like SelectSend but also selects on ctx.Done(),
+// returning index == -2 and ctx.Err() if the context is cancelled or its
+// deadline expires before any case is ready.
+func SelectSendCtx[T any](ctx context.Context, cases ...SendCase[T]) (index int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[select send panic] %v", r)
+		}
+	}()
+	scases := make([]reflect.SelectCase, 0, len(cases)+1)
+	origIndex := make([]int, 0, len(cases))
+	defaultIdx := -1
+	for i, c := range cases {
+		if c.Default {
+			if defaultIdx != -1 {
+				return -1, fmt.Errorf("[select has more than one default case]")
+			}
+			defaultIdx = i
+			continue
+		}
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(c.Chan), Send: reflect.ValueOf(c.Value)})
+		origIndex = append(origIndex, i)
+	}
+	ctxCase := len(scases)
+	scases = append(scases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	if defaultIdx != -1 {
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+	chosen, _, _ := reflect.Select(scases)
+	if defaultIdx != -1 && chosen == len(scases)-1 {
+		return -1, nil
+	}
+	if chosen == ctxCase {
+		return -2, ctx.Err()
+	}
+	return origIndex[chosen], nil
+}
+
+// SelectReceiveCtx behaves like SelectReceive but also selects on ctx.Done(),
+// returning index == -2 and ctx.Err() if the context is cancelled or its
+// deadline expires before any case is ready.
+func SelectReceiveCtx[T any](ctx context.Context, cases ...ReceiveCase[T]) (index int, value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("[select receive panic] %v", r)
+		}
+	}()
+	scases := make([]reflect.SelectCase, 0, len(cases)+1)
+	origIndex := make([]int, 0, len(cases))
+	defaultIdx := -1
+	for i, c := range cases {
+		if c.Default {
+			if defaultIdx != -1 {
+				return -1, value, fmt.Errorf("[select has more than one default case]")
+			}
+			defaultIdx = i
+			continue
+		}
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.Chan)})
+		origIndex = append(origIndex, i)
+	}
+	ctxCase := len(scases)
+	scases = append(scases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	if defaultIdx != -1 {
+		scases = append(scases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+	chosen, recv, recvOK := reflect.Select(scases)
+	if defaultIdx != -1 && chosen == len(scases)-1 {
+		return -1, value, nil
+	}
+	if chosen == ctxCase {
+		return -2, value, ctx.Err()
+	}
+	index = origIndex[chosen]
+	if !recvOK {
+		return index, value, fmt.Errorf("[receive on closed channel] channel(%v) result(%v)", cases[index].Chan, value)
+	}
+	value, _ = recv.Interface().(T)
+	return index, value, nil
+}
+
 func main() {
 	ch := make(chan int)
 	close(ch)
@@ -0,0 +1,455 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPipeSendReceive(t *testing.T) {
+	a, b := Pipe[int]()
+
+	go func() {
+		if err := a.Send(42); err != nil {
+			t.Errorf("a.Send: %v", err)
+		}
+	}()
+	value, err := b.Receive()
+	if err != nil {
+		t.Fatalf("b.Receive: %v", err)
+	}
+	if value != 42 {
+		t.Fatalf("got %d, want 42", value)
+	}
+
+	go func() {
+		if err := b.Send(7); err != nil {
+			t.Errorf("b.Send: %v", err)
+		}
+	}()
+	value, err = a.Receive()
+	if err != nil {
+		t.Fatalf("a.Receive: %v", err)
+	}
+	if value != 7 {
+		t.Fatalf("got %d, want 7", value)
+	}
+}
+
+func TestPipeClose(t *testing.T) {
+	a, b := Pipe[int]()
+
+	if err := a.Close(); err != nil {
+		t.Fatalf("a.Close: %v", err)
+	}
+	// Closing again, from either end, must be a no-op.
+	if err := a.Close(); err != nil {
+		t.Fatalf("a.Close (second call): %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("b.Close: %v", err)
+	}
+
+	if err := a.Send(1); !errors.Is(err, ErrPipeClosed) {
+		t.Fatalf("a.Send after close: got %v, want ErrPipeClosed", err)
+	}
+	if _, err := b.Receive(); !errors.Is(err, ErrPipeClosed) {
+		t.Fatalf("b.Receive after close: got %v, want ErrPipeClosed", err)
+	}
+}
+
+func TestCloseOnce(t *testing.T) {
+	ch := make(chan int)
+	if err := CloseOnce(ch); err != nil {
+		t.Fatalf("first CloseOnce: %v", err)
+	}
+	if err := CloseOnce(ch); err != nil {
+		t.Fatalf("second CloseOnce: %v", err)
+	}
+
+	// A distinct channel must get its own Once and still be closed.
+	ch2 := make(chan int)
+	if err := CloseOnce(ch2); err != nil {
+		t.Fatalf("CloseOnce on unrelated channel: %v", err)
+	}
+	if _, ok := <-ch2; ok {
+		t.Fatal("CloseOnce on unrelated channel: channel still open")
+	}
+}
+
+func TestSafeChan(t *testing.T) {
+	sc := NewSafeChan[int](1)
+
+	if err := sc.Send(1); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	value, err := sc.Receive()
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if value != 1 {
+		t.Fatalf("got %d, want 1", value)
+	}
+
+	if _, received, err := sc.TryReceive(); err != nil || received {
+		t.Fatalf("TryReceive on empty channel: received=%v err=%v", received, err)
+	}
+
+	if err := sc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// A second close must be a no-op rather than panicking.
+	if err := sc.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if err := sc.Send(2); err == nil {
+		t.Fatal("Send after Close: want error, got nil")
+	}
+}
+
+func TestBindSendChanAndBindReceiveChan(t *testing.T) {
+	tr := NewMemTransport()
+	ch := make(chan int)
+	if err := BindSendChan[int](tr, "nums", ch, JSONCodec[int]{}); err != nil {
+		t.Fatalf("BindSendChan: %v", err)
+	}
+
+	out := make(chan int, 1)
+	sub, err := BindReceiveChan[int](tr, "nums", out, JSONCodec[int]{})
+	if err != nil {
+		t.Fatalf("BindReceiveChan: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	ch <- 99
+	select {
+	case got := <-out:
+		if got != 99 {
+			t.Fatalf("got %d, want 99", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for bound value")
+	}
+	close(ch)
+}
+
+// panicCodec's Encode/Decode always panic, simulating a broken codec
+// implementation (or the nil-codec case the bug report reproduced).
+type panicCodec[T any] struct{}
+
+func (panicCodec[T]) Encode(T) ([]byte, error) {
+	panic("boom")
+}
+
+func (panicCodec[T]) Decode([]byte) (T, error) {
+	panic("boom")
+}
+
+func TestBindSendChanRecoversCodecPanic(t *testing.T) {
+	tr := NewMemTransport()
+	ch := make(chan int, 1)
+
+	var mu sync.Mutex
+	var gotErr error
+	onErr := func(err error) {
+		mu.Lock()
+		gotErr = err
+		mu.Unlock()
+	}
+
+	if err := BindSendChan[int](tr, "panics", ch, panicCodec[int]{}, onErr); err != nil {
+		t.Fatalf("BindSendChan: %v", err)
+	}
+
+	// If the panic in the spawned goroutine escapes, this crashes the test
+	// binary instead of failing the test.
+	ch <- 1
+	close(ch)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		err := gotErr
+		mu.Unlock()
+		if err != nil {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("onErr was never called with the codec panic")
+}
+
+func TestBindReceiveChanRecoversCodecPanic(t *testing.T) {
+	tr := NewMemTransport()
+	out := make(chan int, 1)
+
+	errCh := make(chan error, 1)
+	_, err := BindReceiveChan[int](tr, "panics", out, panicCodec[int]{}, func(err error) {
+		errCh <- err
+	})
+	if err != nil {
+		t.Fatalf("BindReceiveChan: %v", err)
+	}
+
+	// If the panic in the subscription handler escapes, this crashes the
+	// test binary instead of failing the test.
+	if err := tr.Publish("panics", []byte("1")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	select {
+	case <-errCh:
+	case <-time.After(time.Second):
+		t.Fatal("onErr was never called with the codec panic")
+	}
+}
+
+func TestTrySend(t *testing.T) {
+	t.Run("buffered channel with room", func(t *testing.T) {
+		ch := make(chan int, 1)
+		sent, err := TrySend(ch, 1)
+		if !sent || err != nil {
+			t.Fatalf("got sent=%v err=%v, want sent=true err=nil", sent, err)
+		}
+	})
+
+	t.Run("full channel", func(t *testing.T) {
+		ch := make(chan int, 1)
+		ch <- 1
+		sent, err := TrySend(ch, 2)
+		if sent || err != nil {
+			t.Fatalf("got sent=%v err=%v, want sent=false err=nil", sent, err)
+		}
+	})
+
+	t.Run("closed channel", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		sent, err := TrySend(ch, 1)
+		if sent || err == nil {
+			t.Fatalf("got sent=%v err=%v, want sent=false err!=nil", sent, err)
+		}
+	})
+
+	t.Run("nil channel", func(t *testing.T) {
+		var ch chan int
+		sent, err := TrySend(ch, 1)
+		if sent || err == nil {
+			t.Fatalf("got sent=%v err=%v, want sent=false err!=nil", sent, err)
+		}
+	})
+}
+
+func TestTryReceive(t *testing.T) {
+	t.Run("channel with a value ready", func(t *testing.T) {
+		ch := make(chan int, 1)
+		ch <- 1
+		value, received, err := TryReceive(ch)
+		if !received || err != nil || value != 1 {
+			t.Fatalf("got value=%d received=%v err=%v, want value=1 received=true err=nil", value, received, err)
+		}
+	})
+
+	t.Run("empty channel", func(t *testing.T) {
+		ch := make(chan int, 1)
+		_, received, err := TryReceive(ch)
+		if received || err != nil {
+			t.Fatalf("got received=%v err=%v, want received=false err=nil", received, err)
+		}
+	})
+
+	t.Run("closed channel", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		_, received, err := TryReceive(ch)
+		if received || err == nil {
+			t.Fatalf("got received=%v err=%v, want received=false err!=nil", received, err)
+		}
+	})
+
+	t.Run("nil channel", func(t *testing.T) {
+		var ch chan int
+		_, received, err := TryReceive(ch)
+		if received || err == nil {
+			t.Fatalf("got received=%v err=%v, want received=false err!=nil", received, err)
+		}
+	})
+}
+
+func TestSendCtx(t *testing.T) {
+	t.Run("succeeds immediately", func(t *testing.T) {
+		ch := make(chan int, 1)
+		if err := SendCtx(context.Background(), ch, 1); err != nil {
+			t.Fatalf("SendCtx: %v", err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ch := make(chan int) // unbuffered, no reader
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := SendCtx(ctx, ch, 1); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("nil channel", func(t *testing.T) {
+		var ch chan int
+		if err := SendCtx(context.Background(), ch, 1); err == nil {
+			t.Fatal("want error for nil channel, got nil")
+		}
+	})
+}
+
+func TestReceiveCtx(t *testing.T) {
+	t.Run("succeeds immediately", func(t *testing.T) {
+		ch := make(chan int, 1)
+		ch <- 1
+		value, err := ReceiveCtx(context.Background(), ch)
+		if err != nil || value != 1 {
+			t.Fatalf("got value=%d err=%v, want value=1 err=nil", value, err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ch := make(chan int) // unbuffered, no writer
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := ReceiveCtx(ctx, ch); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("nil channel", func(t *testing.T) {
+		var ch chan int
+		if _, err := ReceiveCtx(context.Background(), ch); err == nil {
+			t.Fatal("want error for nil channel, got nil")
+		}
+	})
+}
+
+func TestSendTimeout(t *testing.T) {
+	ch := make(chan int) // unbuffered, no reader
+	if err := SendTimeout(ch, 1, 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestReceiveTimeout(t *testing.T) {
+	ch := make(chan int) // unbuffered, no writer
+	if _, err := ReceiveTimeout(ch, 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestSelectReceive(t *testing.T) {
+	t.Run("receives from the ready case", func(t *testing.T) {
+		ch1 := make(chan int) // never ready
+		ch2 := make(chan int, 1)
+		ch2 <- 42
+		idx, value, err := SelectReceive(ReceiveCase[int]{Chan: ch1}, ReceiveCase[int]{Chan: ch2})
+		if err != nil || idx != 1 || value != 42 {
+			t.Fatalf("got idx=%d value=%d err=%v, want idx=1 value=42 err=nil", idx, value, err)
+		}
+	})
+
+	t.Run("default case when nothing is ready", func(t *testing.T) {
+		ch1 := make(chan int)
+		ch2 := make(chan int)
+		idx, _, err := SelectReceive(ReceiveCase[int]{Chan: ch1}, ReceiveCase[int]{Chan: ch2}, ReceiveCase[int]{Default: true})
+		if err != nil || idx != -1 {
+			t.Fatalf("got idx=%d err=%v, want idx=-1 err=nil", idx, err)
+		}
+	})
+
+	t.Run("closed channel reports an error", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		idx, _, err := SelectReceive(ReceiveCase[int]{Chan: ch})
+		if idx != 0 || err == nil {
+			t.Fatalf("got idx=%d err=%v, want idx=0 err!=nil", idx, err)
+		}
+	})
+
+	t.Run("more than one default case errors", func(t *testing.T) {
+		_, _, err := SelectReceive(ReceiveCase[int]{Default: true}, ReceiveCase[int]{Default: true})
+		if err == nil {
+			t.Fatal("want error for duplicate default cases, got nil")
+		}
+	})
+}
+
+func TestSelectSend(t *testing.T) {
+	t.Run("sends on the ready case", func(t *testing.T) {
+		ch1 := make(chan int) // never ready, no reader
+		ch2 := make(chan int, 1)
+		idx, err := SelectSend(SendCase[int]{Chan: ch1, Value: 1}, SendCase[int]{Chan: ch2, Value: 2})
+		if err != nil || idx != 1 {
+			t.Fatalf("got idx=%d err=%v, want idx=1 err=nil", idx, err)
+		}
+		if got := <-ch2; got != 2 {
+			t.Fatalf("got %d on ch2, want 2", got)
+		}
+	})
+
+	t.Run("default case when nothing is ready", func(t *testing.T) {
+		ch1 := make(chan int) // unbuffered, no reader
+		idx, err := SelectSend(SendCase[int]{Chan: ch1, Value: 1}, SendCase[int]{Default: true})
+		if err != nil || idx != -1 {
+			t.Fatalf("got idx=%d err=%v, want idx=-1 err=nil", idx, err)
+		}
+	})
+
+	t.Run("more than one default case errors", func(t *testing.T) {
+		_, err := SelectSend(SendCase[int]{Default: true}, SendCase[int]{Default: true})
+		if err == nil {
+			t.Fatal("want error for duplicate default cases, got nil")
+		}
+	})
+}
+
+func TestSelectReceiveCtx(t *testing.T) {
+	t.Run("receives before the context is done", func(t *testing.T) {
+		ch := make(chan int, 1)
+		ch <- 5
+		idx, value, err := SelectReceiveCtx(context.Background(), ReceiveCase[int]{Chan: ch})
+		if err != nil || idx != 0 || value != 5 {
+			t.Fatalf("got idx=%d value=%d err=%v, want idx=0 value=5 err=nil", idx, value, err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ch := make(chan int) // unbuffered, no writer
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		idx, _, err := SelectReceiveCtx(ctx, ReceiveCase[int]{Chan: ch})
+		if idx != -2 || !errors.Is(err, context.Canceled) {
+			t.Fatalf("got idx=%d err=%v, want idx=-2 err=context.Canceled", idx, err)
+		}
+	})
+}
+
+func TestSelectSendCtx(t *testing.T) {
+	t.Run("sends before the context is done", func(t *testing.T) {
+		ch := make(chan int, 1)
+		idx, err := SelectSendCtx(context.Background(), SendCase[int]{Chan: ch, Value: 9})
+		if err != nil || idx != 0 {
+			t.Fatalf("got idx=%d err=%v, want idx=0 err=nil", idx, err)
+		}
+		if got := <-ch; got != 9 {
+			t.Fatalf("got %d on ch, want 9", got)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ch := make(chan int) // unbuffered, no reader
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		idx, err := SelectSendCtx(ctx, SendCase[int]{Chan: ch, Value: 1})
+		if idx != -2 || !errors.Is(err, context.Canceled) {
+			t.Fatalf("got idx=%d err=%v, want idx=-2 err=context.Canceled", idx, err)
+		}
+	})
+}